@@ -0,0 +1,180 @@
+package limio
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+//TokenBucket is a token-bucket rate limiter that can be shared across many
+//Readers so that N consumers cooperatively obey a single cap, rather than
+//each enforcing its own independent rate. Tokens accrue at rate per second
+//up to burst, and are spent by Allow, AllowN, WaitN and ReserveN.
+type TokenBucket struct {
+	mu sync.Mutex
+
+	rate  float64
+	burst float64
+
+	tokens float64
+	last   time.Time
+}
+
+//NewTokenBucket returns a TokenBucket that allows up to rate tokens per
+//second, with at most burst tokens available at once. The bucket starts
+//full.
+func NewTokenBucket(rate float64, burst uint64) *TokenBucket {
+	return &TokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+//refunder is anything a Reservation can return unused tokens to. TokenBucket
+//satisfies it directly; a chain of buckets (see LimitManager) satisfies it
+//by refunding each bucket on the path.
+type refunder interface {
+	refundTokens(n uint64)
+}
+
+//rateLimiter is the capability Reader needs from a rate source: reserve n
+//tokens as of now, and report the largest chunk worth reserving at once.
+//TokenBucket and the manager's path bucket both implement it, so a Reader
+//doesn't need to know whether it's bound to a single bucket or a tree.
+type rateLimiter interface {
+	ReserveN(now time.Time, n uint64) *Reservation
+	maxChunk() uint64
+}
+
+//Reservation is the result of reserving n tokens from a rateLimiter. If the
+//tokens could not be taken immediately, Delay reports how long the caller
+//must wait before acting; unused tokens can be returned with Cancel.
+type Reservation struct {
+	b      refunder
+	tokens uint64
+	act    time.Time
+}
+
+//Delay reports how long to wait before the reserved tokens may be used.
+func (res *Reservation) Delay() time.Duration {
+	if res == nil || res.act.IsZero() {
+		return 0
+	}
+	d := res.act.Sub(time.Now())
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+//Cancel returns any unused portion of the reservation's tokens to the
+//bucket. It is safe to call more than once; only the first call has an
+//effect.
+func (res *Reservation) Cancel() {
+	if res == nil || res.tokens == 0 || res.b == nil {
+		return
+	}
+	res.b.refundTokens(res.tokens)
+	res.tokens = 0
+}
+
+//refundPartial returns to the bucket(s) whatever portion of the reservation
+//wasn't actually used, e.g. when a Read fills fewer bytes than reserved.
+func (res *Reservation) refundPartial(used uint64) {
+	if res == nil || used >= res.tokens || res.b == nil {
+		return
+	}
+	res.b.refundTokens(res.tokens - used)
+	res.tokens = used
+}
+
+func (b *TokenBucket) advance(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+}
+
+func (b *TokenBucket) refundTokens(n uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = math.Min(b.burst, b.tokens+float64(n))
+}
+
+func (b *TokenBucket) maxChunk() uint64 {
+	return uint64(b.burst)
+}
+
+//clone returns a new, independently-metered TokenBucket with the same rate
+//and burst as b, full to start. Used wherever a single configured cap needs
+//to govern two sides of a transfer (e.g. a Copy's read and write) without
+//both sides spending from, and so double-charging, the same pool of
+//tokens for the same bytes.
+func (b *TokenBucket) clone() *TokenBucket {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return NewTokenBucket(b.rate, uint64(b.burst))
+}
+
+//Allow reports whether a single token is available now, consuming it if so.
+func (b *TokenBucket) Allow() bool {
+	return b.AllowN(1)
+}
+
+//AllowN reports whether n tokens are available now, consuming them if so.
+func (b *TokenBucket) AllowN(n uint64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance(time.Now())
+	if b.tokens >= float64(n) {
+		b.tokens -= float64(n)
+		return true
+	}
+	return false
+}
+
+//ReserveN takes n tokens from the bucket as of now, returning a Reservation
+//that reports how long the caller must wait before acting on them. The
+//tokens are committed immediately; call Cancel on the reservation to return
+//any that end up unused.
+func (b *TokenBucket) ReserveN(now time.Time, n uint64) *Reservation {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.advance(now)
+	b.tokens -= float64(n)
+
+	act := now
+	if b.tokens < 0 {
+		wait := -b.tokens / b.rate
+		act = now.Add(time.Duration(wait * float64(time.Second)))
+	}
+
+	return &Reservation{b: b, tokens: n, act: act}
+}
+
+//WaitN blocks until n tokens are available, or ctx is done. On cancellation
+//it returns ctx.Err() and refunds the reservation's tokens.
+func (b *TokenBucket) WaitN(ctx context.Context, n uint64) error {
+	res := b.ReserveN(time.Now(), n)
+
+	delay := res.Delay()
+	if delay == 0 {
+		return nil
+	}
+
+	t := time.NewTimer(delay)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		res.Cancel()
+		return ctx.Err()
+	}
+}
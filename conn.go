@@ -0,0 +1,89 @@
+package limio
+
+import (
+	"io"
+	"net"
+)
+
+//Copy is io.Copy's counterpart for rate-limited transfers: it routes the
+//read from src and the write to dst through independent TokenBuckets
+//derived from lim's rate and burst, so both directions are governed by the
+//same configured cap without the read and the write each spending tokens
+//for what is, on the wire, the same bytes.
+func Copy(dst io.Writer, src io.Reader, lim *TokenBucket) (written int64, err error) {
+	r := NewReader(src)
+	r.SetBucket(lim.clone())
+
+	w := NewWriter(dst)
+	w.SetBucket(lim.clone())
+
+	return io.Copy(w, r)
+}
+
+//LimitedConn wraps a net.Conn so that each direction is paced against its
+//own TokenBucket derived from a single configured cap, letting callers
+//throttle a connection with one value instead of plumbing a Reader and
+//Writer through separately.
+type LimitedConn struct {
+	net.Conn
+
+	r *Reader
+	w *Writer
+}
+
+//NewLimitedConn wraps c so that Read and Write each draw from their own
+//bucket cloned from lim's rate and burst.
+func NewLimitedConn(c net.Conn, lim *TokenBucket) *LimitedConn {
+	r := NewReader(c)
+	r.SetBucket(lim.clone())
+
+	w := NewWriter(c)
+	w.SetBucket(lim.clone())
+
+	return &LimitedConn{Conn: c, r: r, w: w}
+}
+
+func (c *LimitedConn) Read(p []byte) (int, error) {
+	return c.r.Read(p)
+}
+
+func (c *LimitedConn) Write(p []byte) (int, error) {
+	return c.w.Write(p)
+}
+
+//LimitedListener wraps a net.Listener so every accepted connection comes
+//back as a LimitedConn, with all of them sharing one read-side bucket and
+//one write-side bucket (both cloned once from lim), throttling an entire
+//listener's reads and writes to lim's rate/burst with a single call
+//instead of limiting each connection by hand.
+type LimitedListener struct {
+	net.Listener
+
+	rBucket *TokenBucket
+	wBucket *TokenBucket
+}
+
+//NewLimitedListener wraps l so every Accept'ed net.Conn shares a read-side
+//and a write-side bucket, each cloned from lim's rate and burst.
+func NewLimitedListener(l net.Listener, lim *TokenBucket) *LimitedListener {
+	return &LimitedListener{
+		Listener: l,
+		rBucket:  lim.clone(),
+		wBucket:  lim.clone(),
+	}
+}
+
+func (l *LimitedListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	r := NewReader(c)
+	r.SetBucket(l.rBucket)
+
+	w := NewWriter(c)
+	w.SetBucket(l.wBucket)
+
+	return &LimitedConn{Conn: c, r: r, w: w}, nil
+}
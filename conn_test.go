@@ -0,0 +1,134 @@
+package limio
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+//fullReader hands back all of its data in a single Read, reporting io.EOF
+//alongside the final bytes rather than on a separate call -- the common
+//shape for a bounded in-memory or protocol-framed source, and the shape
+//that makes Copy's bucket use observable without an unrelated end-of-file
+//probe muddying the timing.
+type fullReader struct{ data []byte }
+
+func (r *fullReader) Read(p []byte) (int, error) {
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	if len(r.data) == 0 {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+//TestCopyDoesNotDoubleChargeBucket guards against Copy wiring the same
+//TokenBucket to both the Reader and the Writer: since both sides would
+//then spend tokens for the same bytes, a copy that fits entirely within
+//the bucket's burst would incorrectly have to wait for a refill partway
+//through instead of completing immediately.
+func TestCopyDoesNotDoubleChargeBucket(t *testing.T) {
+	lim := NewTokenBucket(100, 100)
+	src := &fullReader{data: make([]byte, 100)}
+	var dst bytes.Buffer
+
+	start := time.Now()
+	n, err := Copy(&dst, src, lim)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	if n != 100 {
+		t.Fatalf("Copy wrote %d bytes, want 100", n)
+	}
+	if elapsed > 200*time.Millisecond {
+		t.Fatalf("Copy of 100 bytes through a burst-100 bucket took %v; read and write sides are spending the bucket twice for the same data", elapsed)
+	}
+}
+
+//TestLimitedConnUsesIndependentBuckets mirrors the Copy test for
+//LimitedConn: Read and Write are driven independently (here, sequentially
+//against a loopback pipe) and neither should wait on the other's spend
+//from the same cap.
+func TestLimitedConnUsesIndependentBuckets(t *testing.T) {
+	srvConn, cliConn := net.Pipe()
+	defer srvConn.Close()
+	defer cliConn.Close()
+
+	lim := NewTokenBucket(100, 100)
+	lc := NewLimitedConn(cliConn, lim)
+
+	payload := make([]byte, 100)
+	done := make(chan struct{})
+	go func() {
+		srvConn.Write(payload)
+		buf := make([]byte, 100)
+		io.ReadFull(srvConn, buf)
+		close(done)
+	}()
+
+	start := time.Now()
+	buf := make([]byte, 100)
+	if _, err := io.ReadFull(lc, buf); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if _, err := lc.Write(payload); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	<-done
+
+	if elapsed > 200*time.Millisecond {
+		t.Fatalf("LimitedConn round trip of 100 bytes each way through a burst-100 bucket took %v; read and write sides are sharing a bucket", elapsed)
+	}
+}
+
+//TestLimitedListenerSharesBucketAcrossConns guards the other half of
+//LimitedListener's contract: every accepted connection shares the same
+//per-direction bucket, so a second connection's read must wait out the
+//first connection's spend from the same cap.
+func TestLimitedListenerSharesBucketAcrossConns(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer ln.Close()
+
+	lim := NewTokenBucket(100, 100)
+	ll := NewLimitedListener(ln, lim)
+
+	go func() {
+		for i := 0; i < 2; i++ {
+			c, err := net.Dial("tcp", ln.Addr().String())
+			if err != nil {
+				return
+			}
+			c.Write(make([]byte, 100))
+			c.Close()
+		}
+	}()
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		c, err := ll.Accept()
+		if err != nil {
+			t.Fatalf("Accept: %v", err)
+		}
+		buf := make([]byte, 100)
+		if _, err := io.ReadFull(c, buf); err != nil {
+			t.Fatalf("Read %d: %v", i, err)
+		}
+		c.Close()
+	}
+	elapsed := time.Since(start)
+
+	// The first 100 bytes empty the shared burst-100 read bucket, so the
+	// second connection's 100 bytes need a full refill (~1s at rate 100/s).
+	if elapsed < 500*time.Millisecond {
+		t.Fatalf("two 100-byte reads on separate conns through a shared burst-100 bucket took %v; the conns are not sharing the read bucket", elapsed)
+	}
+}
@@ -0,0 +1,193 @@
+package limio
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+//Unlimited is the sentinel bucket a Group carries until Limit is called on
+//it. A Reader chained through an Unlimited group inherits whatever cap its
+//ancestors impose, rather than adding a constraint of its own.
+var Unlimited = NewTokenBucket(0, 0)
+
+func (b *TokenBucket) isUnlimited() bool {
+	return b == Unlimited
+}
+
+//LimitManager composes per-stream and global caps into a tree of named
+//Groups, each enforcing its own rate, with every Reader obeying the
+//minimum effective rate along its path from leaf to root. Changing a
+//Group's limit propagates to every descendant Reader immediately, since
+//they all reserve against the same live TokenBuckets rather than a
+//snapshot of them.
+type LimitManager struct {
+	root *Group
+}
+
+//NewManager returns a LimitManager with an Unlimited root group.
+func NewManager() *LimitManager {
+	return &LimitManager{root: newGroup("", nil)}
+}
+
+//Root returns the manager's top-level Group.
+func (m *LimitManager) Root() *Group {
+	return m.root
+}
+
+//Group is one named node in a LimitManager's tree. It owns a TokenBucket
+//(Unlimited until Limit is called) and any number of child Groups, which
+//inherit its cap in addition to their own.
+type Group struct {
+	mu sync.RWMutex
+
+	name     string
+	parent   *Group
+	children map[string]*Group
+	bucket   *TokenBucket
+}
+
+func newGroup(name string, parent *Group) *Group {
+	return &Group{
+		name:     name,
+		parent:   parent,
+		children: make(map[string]*Group),
+		bucket:   Unlimited,
+	}
+}
+
+//Limit sets this Group's own cap to n tokens per t, replacing whatever cap
+//it had. It returns the Group so calls can be chained, e.g.
+//mgr.Root().Limit(10*MB, time.Second). Existing Readers under this Group
+//keep reserving against the same TokenBucket, so the new limit takes
+//effect immediately without reopening them.
+func (g *Group) Limit(n uint64, t time.Duration) *Group {
+	rate := float64(n) / t.Seconds()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.bucket.isUnlimited() {
+		g.bucket = NewTokenBucket(rate, n)
+		return g
+	}
+
+	g.bucket.mu.Lock()
+	g.bucket.rate = rate
+	g.bucket.burst = float64(n)
+	if g.bucket.tokens > g.bucket.burst {
+		g.bucket.tokens = g.bucket.burst
+	}
+	g.bucket.mu.Unlock()
+
+	return g
+}
+
+//Group returns the named child Group, creating it as Unlimited if it
+//doesn't already exist.
+func (g *Group) Group(name string) *Group {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if child, ok := g.children[name]; ok {
+		return child
+	}
+
+	child := newGroup(name, g)
+	g.children[name] = child
+	return child
+}
+
+func (g *Group) bucketRef() *TokenBucket {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.bucket
+}
+
+//path returns the chain of TokenBuckets from this Group up to the root,
+//skipping any that are Unlimited, nearest first.
+func (g *Group) path() []*TokenBucket {
+	var bs []*TokenBucket
+	for cur := g; cur != nil; cur = cur.parent {
+		if b := cur.bucketRef(); !b.isUnlimited() {
+			bs = append(bs, b)
+		}
+	}
+	return bs
+}
+
+//NewReader wraps src in a *Reader that draws from this Group's effective
+//rate: the minimum of its own cap and every ancestor's. Siblings sharing
+//an ancestor's bucket contend for it first-reserved-first-served, the
+//same as any two Readers sharing a plain TokenBucket; there is no
+//weighted or round-robin scheduling between them, so a sibling reserving
+//in a tight loop can starve others sharing the same ancestor cap. The
+//Reader is bound to the Group itself, not a point-in-time list of its
+//buckets, so a Limit call anywhere on the path -- including turning a
+//still-Unlimited ancestor into a capped one after this Reader already
+//exists -- takes effect on its very next Read.
+func (g *Group) NewReader(src io.Reader) *Reader {
+	r := NewReader(src)
+	r.setLimiter(&pathBucket{leaf: g})
+	return r
+}
+
+//pathBucket chains a Group's live ancestry so that a single Reader always
+//observes the minimum of whatever caps currently exist from leaf to root,
+//the way syncthing combines a per-device cap with a global one. It walks
+//g.path() fresh on every call instead of caching it, which is what lets a
+//Limit call reconfigure or newly impose a cap on an already-wired Reader.
+type pathBucket struct {
+	leaf *Group
+}
+
+func (p *pathBucket) maxChunk() uint64 {
+	bs := p.leaf.path()
+	if len(bs) == 0 {
+		return bufsize
+	}
+
+	min := bs[0].maxChunk()
+	for _, b := range bs[1:] {
+		if c := b.maxChunk(); c < min {
+			min = c
+		}
+	}
+	return min
+}
+
+func (p *pathBucket) ReserveN(now time.Time, n uint64) *Reservation {
+	bs := p.leaf.path()
+	if len(bs) == 0 {
+		return &Reservation{b: noRefund{}, tokens: n, act: now}
+	}
+
+	reserved := make([]*TokenBucket, 0, len(bs))
+	act := now
+
+	for _, b := range bs {
+		res := b.ReserveN(now, n)
+		reserved = append(reserved, b)
+		if res.act.After(act) {
+			act = res.act
+		}
+	}
+
+	return &Reservation{b: &pathRefund{buckets: reserved}, tokens: n, act: act}
+}
+
+type pathRefund struct {
+	buckets []*TokenBucket
+}
+
+func (p *pathRefund) refundTokens(n uint64) {
+	for _, b := range p.buckets {
+		b.refundTokens(n)
+	}
+}
+
+//noRefund is the refunder for a Reservation taken while no Group on a
+//pathBucket's path has a cap; there's nothing to give back.
+type noRefund struct{}
+
+func (noRefund) refundTokens(uint64) {}
@@ -0,0 +1,72 @@
+package limio
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+//TestGroupLimitPropagatesAfterReaderCreated guards against NewReader
+//binding a Reader to a point-in-time snapshot of its Group's buckets: a
+//Group that is still Unlimited when a Reader is created must still be
+//able to cap that Reader once Limit is called later.
+func TestGroupLimitPropagatesAfterReaderCreated(t *testing.T) {
+	mgr := NewManager()
+	root := mgr.Root()
+
+	src := bytes.NewReader(make([]byte, 1<<20))
+	r := root.NewReader(src)
+
+	root.Limit(1000, 100*time.Millisecond)
+
+	buf := make([]byte, 1000)
+	if _, err := r.Read(buf); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	// The bucket starts full, so the first 1000-byte read can complete
+	// immediately; the second must not, since it depletes the bucket
+	// Limit just imposed.
+	start := time.Now()
+	if _, err := r.Read(buf); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	if elapsed < time.Millisecond {
+		t.Fatalf("second 1000-byte read completed in %v; Limit set after NewReader was not applied", elapsed)
+	}
+}
+
+//TestGroupReconfigurationUnderLoad calls Limit repeatedly on a Group while
+//a Reader under it is being read from concurrently, guarding against the
+//reconfiguration racing with, or being lost to, an in-flight Read.
+func TestGroupReconfigurationUnderLoad(t *testing.T) {
+	mgr := NewManager()
+	root := mgr.Root()
+	root.Limit(1<<20, time.Millisecond)
+
+	src := bytes.NewReader(make([]byte, 1<<20))
+	r := root.NewReader(src)
+
+	done := make(chan struct{})
+	go func() {
+		buf := make([]byte, 256)
+		for {
+			if _, err := r.Read(buf); err != nil {
+				close(done)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		root.Limit(uint64(1000+i), time.Millisecond)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Read never finished against a Group being reconfigured")
+	}
+}
@@ -0,0 +1,191 @@
+package limio
+
+import (
+	"sync"
+	"time"
+)
+
+//sampleWindow is the minimum interval between Monitor samples. Read loops
+//that pass small slices shouldn't be able to dominate the moving average by
+//sampling far more often than the underlying transfer actually changes.
+const sampleWindow = 100 * time.Millisecond
+
+//emaWindow is the number of samples-per-window used to derive the EMA decay
+//constant w = 2/(N+1), the same smoothing formula flowcontrol uses.
+const emaWindow = 10
+
+//Monitor tracks the throughput of a stream over time: an instantaneous
+//sample rate, an exponential moving average, and a running peak, with
+//optional progress/ETA reporting when the total transfer size is known.
+type Monitor struct {
+	mu sync.Mutex
+
+	start   time.Time
+	active  bool
+	bytes   int64
+	samples int64
+
+	lastSample     time.Time
+	bytesUnsampled int64
+	rSample        float64
+	rEMA           float64
+	rPeak          float64
+
+	total int64
+}
+
+//NewMonitor returns a Monitor ready to record samples, with its start time
+//set to now.
+func NewMonitor() *Monitor {
+	return &Monitor{start: time.Now(), active: true}
+}
+
+//SetTransferSize records the total size of the transfer being monitored so
+//that Progress and ETA can be computed. A total of 0 disables them.
+func (m *Monitor) SetTransferSize(total int64) {
+	m.mu.Lock()
+	m.total = total
+	m.mu.Unlock()
+}
+
+//Update records n additional bytes transferred. It is time-bucketed: calls
+//within sampleWindow of the last sample accumulate into bytesUnsampled
+//without producing a new rSample/rEMA point, so tight Read loops don't
+//skew the average; once a window elapses, rSample is derived from every
+//byte accumulated since the last sample, not just the call that happened
+//to cross the boundary.
+func (m *Monitor) Update(n int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.bytes += int64(n)
+
+	if m.lastSample.IsZero() {
+		m.lastSample = now
+		return
+	}
+
+	m.bytesUnsampled += int64(n)
+
+	elapsed := now.Sub(m.lastSample)
+	if elapsed < sampleWindow {
+		return
+	}
+
+	m.samples++
+	m.rSample = float64(m.bytesUnsampled) / elapsed.Seconds()
+	m.bytesUnsampled = 0
+
+	w := 2.0 / (emaWindow + 1)
+	if m.samples == 1 {
+		m.rEMA = m.rSample
+	} else {
+		m.rEMA = w*m.rSample + (1-w)*m.rEMA
+	}
+
+	if m.rSample > m.rPeak {
+		m.rPeak = m.rSample
+	}
+
+	m.lastSample = now
+}
+
+//Done marks the monitored stream as finished; Status().Active will report
+//false from this point on.
+func (m *Monitor) Done() {
+	m.mu.Lock()
+	m.active = false
+	m.mu.Unlock()
+}
+
+//Progress reports the fraction, in [0,1], of the transfer size completed.
+//It is always 0 if no transfer size was set via SetTransferSize.
+func (m *Monitor) Progress() float32 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.progress()
+}
+
+func (m *Monitor) progress() float32 {
+	if m.total <= 0 {
+		return 0
+	}
+	p := float32(m.bytes) / float32(m.total)
+	if p > 1 {
+		p = 1
+	}
+	return p
+}
+
+//ETA estimates the time remaining to complete the transfer, based on the
+//current EMA rate. It is 0 if no transfer size was set, or the rate is not
+//yet known.
+func (m *Monitor) ETA() time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.eta()
+}
+
+func (m *Monitor) eta() time.Duration {
+	if m.total <= 0 || m.rEMA <= 0 {
+		return 0
+	}
+	rem := m.total - m.bytes
+	if rem <= 0 {
+		return 0
+	}
+	return time.Duration(float64(rem) / m.rEMA * float64(time.Second))
+}
+
+//Status is a point-in-time snapshot of a Monitor's throughput statistics.
+type Status struct {
+	Active   bool
+	Start    time.Time
+	Duration time.Duration
+	Bytes    int64
+	Samples  int64
+	InstRate float64
+	CurRate  float64
+	AvgRate  float64
+	PeakRate float64
+	BytesRem int64
+	TimeRem  time.Duration
+	Progress float32
+}
+
+//Status returns a snapshot of the Monitor's current statistics.
+func (m *Monitor) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	dur := time.Since(m.start)
+
+	var avg float64
+	if dur > 0 {
+		avg = float64(m.bytes) / dur.Seconds()
+	}
+
+	var rem int64
+	if m.total > 0 {
+		rem = m.total - m.bytes
+		if rem < 0 {
+			rem = 0
+		}
+	}
+
+	return Status{
+		Active:   m.active,
+		Start:    m.start,
+		Duration: dur,
+		Bytes:    m.bytes,
+		Samples:  m.samples,
+		InstRate: m.rSample,
+		CurRate:  m.rEMA,
+		AvgRate:  avg,
+		PeakRate: m.rPeak,
+		BytesRem: rem,
+		TimeRem:  m.eta(),
+		Progress: m.progress(),
+	}
+}
@@ -0,0 +1,62 @@
+package limio
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+//TestMonitorUpdateSamplesAccumulatedBytes guards against rSample being
+//derived from only the latest Update call's n instead of every byte
+//accumulated since the last sample point -- exactly the case the
+//100ms time-bucketing exists to handle, several small Reads inside one
+//window.
+func TestMonitorUpdateSamplesAccumulatedBytes(t *testing.T) {
+	m := NewMonitor()
+
+	m.Update(1000)
+	time.Sleep(50 * time.Millisecond)
+	m.Update(2000)
+	time.Sleep(60 * time.Millisecond)
+	m.Update(3000) // ~110ms since the first call: crosses sampleWindow
+
+	got := m.Status().InstRate
+
+	// Bytes accumulated since the last sample point are the 2000 and 3000
+	// calls (the first Update only establishes the starting point), over
+	// the elapsed time since then -- not just the 3000 from the call that
+	// happened to cross the window boundary.
+	const wantMin, wantMax = 35000.0, 60000.0
+	if got < wantMin || got > wantMax {
+		t.Fatalf("InstRate = %v, want roughly 45455 (accumulated bytes / elapsed), not ~27273 (just the latest call)", got)
+	}
+}
+
+//TestMonitorConcurrentUpdateAndStatus guards against Update and Status
+//racing on the Monitor's shared fields -- the common pattern of one
+//goroutine feeding bytes through Update while another polls Status for
+//progress reporting.
+func TestMonitorConcurrentUpdateAndStatus(t *testing.T) {
+	m := NewMonitor()
+	m.SetTransferSize(1 << 20)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			m.Update(100)
+		}
+		m.Done()
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			m.Status()
+		}
+	}()
+
+	wg.Wait()
+}
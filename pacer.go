@@ -0,0 +1,115 @@
+package limio
+
+import (
+	"sync"
+	"time"
+)
+
+//rateSpec is one (n, t) configuration handed to a pacer's goroutine; it
+//replaces spawning a fresh ticker+goroutine per Limit call.
+type rateSpec struct {
+	n uint64
+	t time.Duration
+}
+
+//pacer owns the single long-lived goroutine backing Reader.Limit and
+//Writer.Limit. start lazily spawns it (or hands back the one already
+//running); apply reconfigures it in place; close stops it and lets a
+//later start spawn a fresh one. Shared by Reader and Writer so the fix for
+//one doesn't have to be rediscovered for the other.
+type pacer struct {
+	mu      sync.Mutex
+	running bool
+	cfg     chan rateSpec
+	stopCh  chan struct{}
+	out     chan uint64
+}
+
+//start returns the pacer's channels, starting its goroutine first if it
+//isn't already running.
+func (p *pacer) start() (cfg chan<- rateSpec, out <-chan uint64, stop <-chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.running {
+		return p.cfg, p.out, p.stopCh
+	}
+
+	p.cfg = make(chan rateSpec)
+	p.stopCh = make(chan struct{})
+	p.out = make(chan uint64)
+	p.running = true
+
+	go runPacer(p.cfg, p.out, p.stopCh)
+
+	return p.cfg, p.out, p.stopCh
+}
+
+//apply reconfigures the pacer to (n, t), starting it first if necessary.
+//It never blocks indefinitely: if close raced it and stopped the
+//goroutine it was about to configure, it starts a fresh one and retries.
+func (p *pacer) apply(n uint64, t time.Duration) <-chan uint64 {
+	for {
+		cfg, out, stop := p.start()
+
+		select {
+		case cfg <- rateSpec{n: n, t: t}:
+			return out
+		case <-stop:
+		}
+	}
+}
+
+//close stops the pacer's goroutine, if running, and lets the next start
+//spawn a new one. Safe to call whether or not start was ever called, and
+//safe to call more than once.
+func (p *pacer) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.running {
+		close(p.stopCh)
+		p.running = false
+	}
+}
+
+//runPacer is the pacer goroutine body: one flat select handles
+//reconfiguration, tick delivery and shutdown together. A tick case only
+//arms a send (by pointing sendCh at out) rather than nesting a second
+//select around the send -- nesting would park the goroutine there until
+//something drained out, deaf to cfg in the meantime, which is exactly how
+//two back-to-back Limit calls with nobody reading yet used to deadlock.
+func runPacer(cfg <-chan rateSpec, out chan<- uint64, stop <-chan struct{}) {
+	var tkr *time.Ticker
+	var tickerC <-chan time.Time
+	var sendCh chan<- uint64
+	var n uint64
+
+	defer func() {
+		if tkr != nil {
+			tkr.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case spec := <-cfg:
+			if tkr != nil {
+				tkr.Stop()
+			}
+			n = spec.n
+			tkr = time.NewTicker(spec.t)
+			tickerC = tkr.C
+			sendCh = nil
+
+		case <-tickerC:
+			sendCh = out
+
+		case sendCh <- n:
+			sendCh = nil
+
+		case <-stop:
+			return
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package limio
 
 import (
+	"context"
 	"io"
 	"math"
 	"sync"
@@ -42,6 +43,15 @@ type Reader struct {
 
 	rMut sync.RWMutex
 	rate <-chan uint64
+	pace pacer
+
+	bMut   sync.RWMutex
+	bucket rateLimiter
+
+	rdMut    sync.RWMutex
+	deadline time.Time
+
+	mon *Monitor
 }
 
 func (r *Reader) rater() <-chan uint64 {
@@ -50,7 +60,46 @@ func (r *Reader) rater() <-chan uint64 {
 	return r.rate
 }
 
-func (r *Reader) Read(p []byte) (written int, err error) {
+func (r *Reader) bucketRef() rateLimiter {
+	r.bMut.RLock()
+	defer r.bMut.RUnlock()
+	return r.bucket
+}
+
+//SetBucket attaches a shared TokenBucket to the Reader so that it draws
+//from, and waits on, a cap shared with other Readers rather than enforcing
+//its own. It takes precedence over a rate set via Limit or LimitChan. It is
+//safe to call concurrently with Read, though Read will finish its current
+//iteration under the prior limiter.
+func (r *Reader) SetBucket(b *TokenBucket) {
+	r.setLimiter(b)
+}
+
+//setLimiter is the unexported path SetBucket and LimitManager's readers
+//share; it accepts anything satisfying rateLimiter, not just a bare
+//TokenBucket.
+func (r *Reader) setLimiter(rl rateLimiter) {
+	r.bMut.Lock()
+	r.bucket = rl
+	r.bMut.Unlock()
+}
+
+func (r *Reader) Read(p []byte) (int, error) {
+	ctx := context.Background()
+
+	if d := r.readDeadline(); !d.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, d)
+		defer cancel()
+	}
+
+	return r.ReadContext(ctx, p)
+}
+
+//ReadContext is Read with a context: it returns ctx.Err() as soon as ctx is
+//done, rather than blocking forever on a paused or starved limiter. Any
+//reserved-but-unused tokens are refunded on cancellation.
+func (r *Reader) ReadContext(ctx context.Context, p []byte) (written int, err error) {
 	if r.r == nil {
 		err = io.ErrUnexpectedEOF
 		return
@@ -64,6 +113,29 @@ func (r *Reader) Read(p []byte) (written int, err error) {
 		r.buf = make([]byte, bufsize)
 	}
 
+	if b := r.bucketRef(); b != nil {
+		return r.readBucket(ctx, b, p)
+	}
+
+	return r.readChan(ctx, p)
+}
+
+//SetReadDeadline sets a deadline every subsequent Read (but not
+//ReadContext, whose caller supplies its own context) must complete by,
+//mirroring net.Conn's SetReadDeadline. A zero time.Time disables it.
+func (r *Reader) SetReadDeadline(t time.Time) {
+	r.rdMut.Lock()
+	r.deadline = t
+	r.rdMut.Unlock()
+}
+
+func (r *Reader) readDeadline() time.Time {
+	r.rdMut.RLock()
+	defer r.rdMut.RUnlock()
+	return r.deadline
+}
+
+func (r *Reader) readChan(ctx context.Context, p []byte) (written int, err error) {
 	for written < len(p) {
 		var lim uint64
 		if r.rater() != nil {
@@ -72,11 +144,15 @@ func (r *Reader) Read(p []byte) (written int, err error) {
 				case r.remain = <-r.rater():
 					break
 				default:
-
 					if written > 0 {
 						return
 					}
-					r.remain = <-r.rater()
+
+					select {
+					case r.remain = <-r.rater():
+					case <-ctx.Done():
+						return written, ctx.Err()
+					}
 				}
 			}
 
@@ -96,6 +172,7 @@ func (r *Reader) Read(p []byte) (written int, err error) {
 
 		copy(p[written:], r.buf[:n])
 		written += n
+		r.mon.Update(n)
 
 		if r.rater() != nil {
 			r.remain -= uint64(n)
@@ -104,7 +181,9 @@ func (r *Reader) Read(p []byte) (written int, err error) {
 		if err != nil {
 			if err == io.EOF {
 				r.eof = true
+				r.mon.Done()
 				r.done.Done()
+				r.Close()
 			}
 
 			return
@@ -113,6 +192,64 @@ func (r *Reader) Read(p []byte) (written int, err error) {
 	return
 }
 
+//readBucket is Read's path for when a shared TokenBucket is attached. It
+//reserves, and reads, at most one chunk no larger than the bucket's burst
+//per call rather than looping to fill p completely: p is usually much
+//larger than any reasonable burst (e.g. io.Copy's internal buffer), and
+//looping would mean reserving a second chunk just to find out the first
+//one already exhausted the underlying reader, paying a full refill wait
+//to discover an EOF. A short read here is fine -- callers of io.Reader
+//already have to handle that. Any portion of the chunk not actually
+//filled by r.r.Read, or not used because ctx was done before the wait
+//elapsed, is refunded.
+func (r *Reader) readBucket(ctx context.Context, b rateLimiter, p []byte) (written int, err error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+
+	lim := uint64(len(p))
+	if chunk := b.maxChunk(); lim > chunk {
+		lim = chunk
+	}
+	if lim == 0 {
+		lim = 1
+	}
+	if lim > uint64(len(r.buf)) {
+		lim = uint64(len(r.buf))
+	}
+
+	res := b.ReserveN(time.Now(), lim)
+	if d := res.Delay(); d > 0 {
+		t := time.NewTimer(d)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			res.Cancel()
+			return 0, ctx.Err()
+		}
+	}
+
+	var n int
+	n, err = r.r.Read(r.buf[:lim])
+	res.refundPartial(uint64(n))
+
+	copy(p, r.buf[:n])
+	written = n
+	r.mon.Update(n)
+
+	if err == io.EOF {
+		r.eof = true
+		r.mon.Done()
+		r.done.Done()
+		r.Close()
+	}
+
+	return
+}
+
 //Limit provides a basic means for limiting a Reader. Given n bytes per t
 //time, it does its best to maintain a constant rate with a high degree of
 //accuracy to allow other algorithms (such as TCP window sizing, e.g.) to
@@ -133,23 +270,11 @@ func (r *Reader) Limit(n uint64, t time.Duration) {
 		t = window
 	}
 
-	//TODO make sure no memory leaks
-	ch := make(chan uint64)
+	ch := r.pace.apply(n, t)
 
 	r.rMut.Lock()
 	r.rate = ch
 	r.rMut.Unlock()
-
-	tkr := time.NewTicker(t)
-	go func() {
-		for _ = range tkr.C {
-			if r.eof {
-				return
-			}
-
-			ch <- n
-		}
-	}()
 }
 
 func (r *Reader) LimitChan(c <-chan uint64) {
@@ -158,6 +283,39 @@ func (r *Reader) LimitChan(c <-chan uint64) {
 	r.rMut.Unlock()
 }
 
+//Unlimit removes the rate set by Limit or LimitChan so Read proceeds as fast
+//as the underlying io.Reader allows. A bucket attached with SetBucket (or
+//through a Group) is not removed and still paces Read. If Limit had started a pacing goroutine, it keeps
+//running so a later Limit call doesn't need to spawn a new one; its ticks
+//are simply queued behind nothing, since there's no reader for them.
+func (r *Reader) Unlimit() {
+	r.rMut.Lock()
+	r.rate = nil
+	r.rMut.Unlock()
+}
+
+//Close stops the pacing goroutine started by Limit, if any, and lets a
+//later Limit call start a fresh one. It is safe to call even if Limit was
+//never invoked, and safe to call more than once.
+func (r *Reader) Close() error {
+	r.pace.close()
+	return nil
+}
+
+//Status returns a snapshot of the Reader's throughput: instantaneous and
+//average rates, a running peak, and, if SetTransferSize was called,
+//progress and an ETA. It answers the "is this stream too slow?" question
+//that a bare io.Reader can't.
+func (r *Reader) Status() Status {
+	return r.mon.Status()
+}
+
+//SetTransferSize records the total size of the stream being read so that
+//Status can report Progress and TimeRem.
+func (r *Reader) SetTransferSize(total int64) {
+	r.mon.SetTransferSize(total)
+}
+
 //Close will block until eof is reached. Once reached, any errors will be
 //returned. It is intended to provide synchronization for external channel
 //managers
@@ -179,6 +337,7 @@ func NewReader(r io.Reader) *Reader {
 			buf:  make([]byte, bufsize),
 			done: &sync.WaitGroup{},
 			rMut: sync.RWMutex{},
+			mon:  NewMonitor(),
 		}
 
 		nr.done.Add(1)
@@ -0,0 +1,130 @@
+package limio
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+//TestReaderLimitTwiceDoesNotDeadlock guards against the pacing goroutine
+//parking in a blocking send to its out channel (with nobody reading it yet) and
+//going deaf to cfg, which would make a second Limit call hang forever.
+func TestReaderLimitTwiceDoesNotDeadlock(t *testing.T) {
+	r := NewReader(strings.NewReader("hello world"))
+
+	done := make(chan struct{})
+	go func() {
+		r.Limit(10, time.Second)
+		r.Limit(20, time.Second)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("two back-to-back Limit calls deadlocked")
+	}
+
+	r.Close()
+}
+
+//TestReaderLimitDoesNotLeakGoroutines is the regression test the
+//chunk0-6 request asked for: calling Limit repeatedly must reconfigure
+//the Reader's single pacing goroutine rather than spawning a new one
+//each time.
+func TestReaderLimitDoesNotLeakGoroutines(t *testing.T) {
+	r := NewReader(strings.NewReader("hello world"))
+	defer r.Close()
+
+	runtime.GC()
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 1000; i++ {
+		r.Limit(1024, time.Second)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	after := before
+	for time.Now().Before(deadline) {
+		runtime.GC()
+		after = runtime.NumGoroutine()
+		if after <= before+1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if after > before+1 {
+		t.Fatalf("goroutine count grew from %d to %d after 1000 Limit calls", before, after)
+	}
+}
+
+//TestReaderReadContextCancelRefundsReservation guards ReadContext's
+//cancellation path: against a bucket too starved to act on immediately,
+//canceling ctx must make Read return promptly with ctx.Err() rather than
+//block out the reservation's delay, and must return the reservation's
+//tokens rather than leaving the bucket charged for a read that never
+//happened.
+func TestReaderReadContextCancelRefundsReservation(t *testing.T) {
+	b := NewTokenBucket(10, 10)
+	b.AllowN(10) // drain the bucket so any reservation has to wait
+
+	r := NewReader(strings.NewReader("hello world"))
+	r.SetBucket(b)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	buf := make([]byte, 5)
+	_, err := r.ReadContext(ctx, buf)
+	elapsed := time.Since(start)
+
+	if err != context.Canceled {
+		t.Fatalf("ReadContext error = %v, want context.Canceled", err)
+	}
+	if elapsed > 200*time.Millisecond {
+		t.Fatalf("ReadContext took %v to return after ctx was canceled; a starved bucket should not block cancellation", elapsed)
+	}
+
+	// If the canceled read's 5-token reservation was refunded, the bucket
+	// is back to 0 (not -5), so reserving another 5 needs only one more
+	// refill cycle (~500ms at rate 10/s) rather than two (~1s).
+	res := b.ReserveN(time.Now(), 5)
+	d := res.Delay()
+	res.Cancel()
+	if d > 600*time.Millisecond {
+		t.Fatalf("reservation delay after cancellation = %v, want ~500ms; the canceled read's tokens were not refunded", d)
+	}
+}
+
+//TestReaderSetReadDeadlineExpires guards Read's deadline path, the
+//net.Conn-style counterpart to ReadContext's caller-supplied ctx: a
+//deadline that passes while Read waits on a starved bucket's reservation
+//must make Read return promptly with context.DeadlineExceeded, rather than
+//block out the reservation's delay.
+func TestReaderSetReadDeadlineExpires(t *testing.T) {
+	b := NewTokenBucket(10, 10)
+	b.AllowN(10) // drain the bucket so any reservation has to wait
+
+	r := NewReader(strings.NewReader("hello world"))
+	r.SetBucket(b)
+	r.SetReadDeadline(time.Now().Add(20 * time.Millisecond))
+
+	start := time.Now()
+	buf := make([]byte, 5)
+	_, err := r.Read(buf)
+	elapsed := time.Since(start)
+
+	if err != context.DeadlineExceeded {
+		t.Fatalf("Read error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed > 200*time.Millisecond {
+		t.Fatalf("Read took %v to return after its deadline passed; a starved bucket should not block it", elapsed)
+	}
+}
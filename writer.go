@@ -0,0 +1,191 @@
+package limio
+
+import (
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+//Writer is the write-side counterpart to Reader: it wraps an io.Writer and
+//paces Write the same way Reader paces Read, either via Limit/LimitChan's
+//ticker-driven channel or a shared TokenBucket set with SetBucket.
+type Writer struct {
+	w   io.Writer
+	buf []byte
+
+	remain uint64
+
+	rMut sync.RWMutex
+	rate <-chan uint64
+	pace pacer
+
+	bMut   sync.RWMutex
+	bucket rateLimiter
+}
+
+//NewWriter takes an io.Writer and returns a Limitable Writer.
+func NewWriter(w io.Writer) *Writer {
+	switch w := w.(type) {
+	case *Writer:
+		return w
+	default:
+		return &Writer{
+			w:   w,
+			buf: make([]byte, bufsize),
+		}
+	}
+}
+
+func (w *Writer) rater() <-chan uint64 {
+	w.rMut.RLock()
+	defer w.rMut.RUnlock()
+	return w.rate
+}
+
+func (w *Writer) bucketRef() rateLimiter {
+	w.bMut.RLock()
+	defer w.bMut.RUnlock()
+	return w.bucket
+}
+
+//SetBucket attaches a shared TokenBucket to the Writer, the same way
+//Reader.SetBucket does for reads. It takes precedence over a rate set via
+//Limit or LimitChan.
+func (w *Writer) SetBucket(b *TokenBucket) {
+	w.setLimiter(b)
+}
+
+func (w *Writer) setLimiter(rl rateLimiter) {
+	w.bMut.Lock()
+	w.bucket = rl
+	w.bMut.Unlock()
+}
+
+func (w *Writer) Write(p []byte) (written int, err error) {
+	if w.w == nil {
+		err = io.ErrUnexpectedEOF
+		return
+	}
+
+	if b := w.bucketRef(); b != nil {
+		return w.writeBucket(b, p)
+	}
+
+	for written < len(p) {
+		var lim uint64
+		if w.rater() != nil {
+			if w.remain == 0 {
+				select {
+				case w.remain = <-w.rater():
+					break
+				default:
+
+					if written > 0 {
+						return
+					}
+					w.remain = <-w.rater()
+				}
+			}
+
+			lim = w.remain
+		}
+
+		if lim == 0 {
+			lim -= 1
+		}
+
+		if lim > uint64(len(p[written:])) {
+			lim = uint64(len(p[written:]))
+		}
+
+		var n int
+		n, err = w.w.Write(p[written : written+int(lim)])
+		written += n
+
+		if w.rater() != nil {
+			w.remain -= uint64(n)
+		}
+
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+//writeBucket is Write's path for when a shared TokenBucket is attached. p
+//is chunked into pieces no larger than the bucket's burst, each waited for
+//in turn, with any unused portion of a chunk (a short write) refunded.
+func (w *Writer) writeBucket(b rateLimiter, p []byte) (written int, err error) {
+	for written < len(p) {
+		lim := uint64(len(p[written:]))
+		if chunk := b.maxChunk(); lim > chunk {
+			lim = chunk
+		}
+		if lim == 0 {
+			lim = 1
+		}
+
+		res := b.ReserveN(time.Now(), lim)
+		if d := res.Delay(); d > 0 {
+			time.Sleep(d)
+		}
+
+		var n int
+		n, err = w.w.Write(p[written : written+int(lim)])
+		res.refundPartial(uint64(n))
+		written += n
+
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+//Limit provides a basic means for limiting a Writer, identical in effect to
+//Reader.Limit: given n bytes per t time, it does its best to maintain a
+//constant rate. Like Reader, it reconfigures a single long-lived pacing
+//goroutine rather than spawning a new one per call.
+func (w *Writer) Limit(n uint64, t time.Duration) {
+	ratio := float64(t) / float64(window)
+	nPer := float64(n) / ratio
+	n = uint64(nPer)
+
+	if nPer < 1.0 {
+		t = time.Duration(math.Pow(nPer, -1))
+		n = 1
+	} else {
+		t = window
+	}
+
+	ch := w.pace.apply(n, t)
+
+	w.rMut.Lock()
+	w.rate = ch
+	w.rMut.Unlock()
+}
+
+func (w *Writer) LimitChan(c <-chan uint64) {
+	w.rMut.Lock()
+	w.rate = c
+	w.rMut.Unlock()
+}
+
+//Unlimit removes the rate set by Limit or LimitChan so Write proceeds as
+//fast as the underlying io.Writer allows, mirroring Reader.Unlimit. A
+//bucket attached with SetBucket is not removed and still paces Write.
+func (w *Writer) Unlimit() {
+	w.rMut.Lock()
+	w.rate = nil
+	w.rMut.Unlock()
+}
+
+//Close stops the pacing goroutine started by Limit, if any, mirroring
+//Reader.Close. Safe to call even if Limit was never invoked, and safe to
+//call more than once.
+func (w *Writer) Close() error {
+	w.pace.close()
+	return nil
+}
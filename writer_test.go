@@ -0,0 +1,37 @@
+package limio
+
+import (
+	"io"
+	"runtime"
+	"testing"
+	"time"
+)
+
+//TestWriterLimitDoesNotLeakGoroutines guards against Writer.Limit spawning
+//a fresh ticker+goroutine, with no stop mechanism at all, on every call.
+func TestWriterLimitDoesNotLeakGoroutines(t *testing.T) {
+	w := NewWriter(io.Discard)
+	defer w.Close()
+
+	runtime.GC()
+	before := runtime.NumGoroutine()
+
+	for i := 0; i < 50; i++ {
+		w.Limit(1024, time.Second)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	after := before
+	for time.Now().Before(deadline) {
+		runtime.GC()
+		after = runtime.NumGoroutine()
+		if after <= before+1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if after > before+1 {
+		t.Fatalf("goroutine count grew from %d to %d after 50 Limit calls", before, after)
+	}
+}